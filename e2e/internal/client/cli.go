@@ -4,26 +4,35 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
-
-	"github.com/onsi/ginkgo"
-	"github.com/onsi/gomega/gexec"
 )
 
-// CLI is a wrapper around os.execs.
-type CLI struct {
+// ExecCLI is a wrapper around os.execs that shells out to a prebuilt copilot
+// binary. It predates the in-process SDK (see Client) and is kept around so
+// existing callers that depend on exact CLI stdout/stderr behavior keep working.
+type ExecCLI struct {
 	path string
 }
 
+// CLI is an alias of ExecCLI, kept so existing `*client.CLI` field/var
+// declarations across the e2e suites keep compiling unchanged.
+//
+// Deprecated: refer to ExecCLI directly in new code.
+type CLI = ExecCLI
+
 // AppInitRequest contains the parameters for calling copilot app init.
 type AppInitRequest struct {
 	AppName string
 	Domain  string
 	Tags    map[string]string
+
+	Context context.Context
+	Options *ExecOptions
 }
 
 // InitRequest contains the parameters for calling copilot init.
@@ -35,6 +44,9 @@ type InitRequest struct {
 	Dockerfile   string
 	WorkloadType string
 	SvcPort      string
+
+	Context context.Context
+	Options *ExecOptions
 }
 
 // EnvInitRequest contains the parameters for calling copilot env init.
@@ -46,6 +58,9 @@ type EnvInitRequest struct {
 	CustomizedEnv bool
 	VPCImport     EnvInitRequestVPCImport
 	VPCConfig     EnvInitRequestVPCConfig
+
+	Context context.Context
+	Options *ExecOptions
 }
 
 // EnvInitRequestVPCImport contains the parameters for configuring VPC import when
@@ -73,6 +88,9 @@ type EnvInitRequestVPCConfig struct {
 type EnvShowRequest struct {
 	AppName string
 	EnvName string
+
+	Context context.Context
+	Options *ExecOptions
 }
 
 // SvcInitRequest contains the parameters for calling copilot svc init.
@@ -81,12 +99,18 @@ type SvcInitRequest struct {
 	SvcType    string
 	Dockerfile string
 	SvcPort    string
+
+	Context context.Context
+	Options *ExecOptions
 }
 
 // SvcShowRequest contains the parameters for calling copilot svc show.
 type SvcShowRequest struct {
 	Name    string
 	AppName string
+
+	Context context.Context
+	Options *ExecOptions
 }
 
 // SvcStatusRequest contains the parameters for calling copilot svc status.
@@ -94,6 +118,9 @@ type SvcStatusRequest struct {
 	Name    string
 	AppName string
 	EnvName string
+
+	Context context.Context
+	Options *ExecOptions
 }
 
 // SvcLogsRequest contains the parameters for calling copilot svc logs.
@@ -102,6 +129,9 @@ type SvcLogsRequest struct {
 	EnvName string
 	Name    string
 	Since   string
+
+	Context context.Context
+	Options *ExecOptions
 }
 
 // SvcDeployInput contains the parameters for calling copilot svc deploy.
@@ -109,6 +139,9 @@ type SvcDeployInput struct {
 	Name     string
 	EnvName  string
 	ImageTag string
+
+	Context context.Context
+	Options *ExecOptions
 }
 
 // TaskRunInput contains the parameters for calling copilot task run.
@@ -129,10 +162,21 @@ type TaskRunInput struct {
 
 	Default bool
 	Follow  bool
+
+	Context context.Context
+	Options *ExecOptions
 }
 
-// NewCLI returns a wrapper around CLI
-func NewCLI() (*CLI, error) {
+// NewCLI returns a wrapper around ExecCLI.
+//
+// Deprecated: use NewExecCLI, or NewSDK if you don't need to drive the
+// prebuilt copilot binary specifically.
+func NewCLI() (*ExecCLI, error) {
+	return NewExecCLI()
+}
+
+// NewExecCLI returns a wrapper around ExecCLI.
+func NewExecCLI() (*ExecCLI, error) {
 	// These tests should be run in a dockerfile so that
 	// your file system and docker image repo isn't polluted
 	// with test data and files. Since this is going to run
@@ -142,7 +186,7 @@ func NewCLI() (*CLI, error) {
 		return nil, err
 	}
 
-	return &CLI{
+	return &ExecCLI{
 		path: cliPath,
 	}, nil
 }
@@ -150,15 +194,15 @@ func NewCLI() (*CLI, error) {
 /*Help runs
 copilot --help
 */
-func (cli *CLI) Help() (string, error) {
-	return cli.exec(exec.Command(cli.path, "--help"))
+func (cli *ExecCLI) Help() (string, error) {
+	return cli.exec(context.Background(), nil, exec.Command(cli.path, "--help"))
 }
 
 /*Version runs:
 copilot --version
 */
-func (cli *CLI) Version() (string, error) {
-	return cli.exec(exec.Command(cli.path, "--version"))
+func (cli *ExecCLI) Version() (string, error) {
+	return cli.exec(context.Background(), nil, exec.Command(cli.path, "--version"))
 }
 
 /*Init runs:
@@ -171,14 +215,14 @@ copilot init
 	--port $port
 	--deploy (optionally)
 */
-func (cli *CLI) Init(opts *InitRequest) (string, error) {
+func (cli *ExecCLI) Init(opts *InitRequest) (string, error) {
 	var deployOption string
 
 	if opts.Deploy {
 		deployOption = "--deploy"
 	}
 
-	return cli.exec(
+	return cli.exec(opts.Context, opts.Options,
 		exec.Command(cli.path, "init",
 			"--app", opts.AppName,
 			"--name", opts.WorkloadName,
@@ -196,7 +240,7 @@ copilot svc init
 	--dockerfile $d
 	--port $port
 */
-func (cli *CLI) SvcInit(opts *SvcInitRequest) (string, error) {
+func (cli *ExecCLI) SvcInit(opts *SvcInitRequest) (string, error) {
 	args := []string{
 		"svc",
 		"init",
@@ -208,7 +252,7 @@ func (cli *CLI) SvcInit(opts *SvcInitRequest) (string, error) {
 	if opts.SvcPort != "" {
 		args = append(args, "--port", opts.SvcPort)
 	}
-	return cli.exec(
+	return cli.exec(opts.Context, opts.Options,
 		exec.Command(cli.path, args...))
 }
 
@@ -218,8 +262,8 @@ copilot svc show
 	--name $n
 	--json
 */
-func (cli *CLI) SvcShow(opts *SvcShowRequest) (*SvcShowOutput, error) {
-	svcJSON, svcShowErr := cli.exec(
+func (cli *ExecCLI) SvcShow(opts *SvcShowRequest) (*SvcShowOutput, error) {
+	svcJSON, svcShowErr := cli.exec(opts.Context, opts.Options,
 		exec.Command(cli.path, "svc", "show",
 			"--app", opts.AppName,
 			"--name", opts.Name,
@@ -239,8 +283,8 @@ copilot svc status
 	--name $n
 	--json
 */
-func (cli *CLI) SvcStatus(opts *SvcStatusRequest) (*SvcStatusOutput, error) {
-	svcJSON, svcStatusErr := cli.exec(
+func (cli *ExecCLI) SvcStatus(opts *SvcStatusRequest) (*SvcStatusOutput, error) {
+	svcJSON, svcStatusErr := cli.exec(opts.Context, opts.Options,
 		exec.Command(cli.path, "svc", "status",
 			"--app", opts.AppName,
 			"--name", opts.Name,
@@ -259,8 +303,8 @@ copilot svc delete
 	--name $n
 	--yes
 */
-func (cli *CLI) SvcDelete(serviceName string) (string, error) {
-	return cli.exec(
+func (cli *ExecCLI) SvcDelete(serviceName string) (string, error) {
+	return cli.exec(context.Background(), nil,
 		exec.Command(cli.path, "svc", "delete",
 			"--name", serviceName,
 			"--yes"))
@@ -272,8 +316,8 @@ copilot svc deploy
 	--env $e
 	--tag $t
 */
-func (cli *CLI) SvcDeploy(opts *SvcDeployInput) (string, error) {
-	return cli.exec(
+func (cli *ExecCLI) SvcDeploy(opts *SvcDeployInput) (string, error) {
+	return cli.exec(opts.Context, opts.Options,
 		exec.Command(cli.path, "svc", "deploy",
 			"--name", opts.Name,
 			"--env", opts.EnvName,
@@ -285,8 +329,8 @@ copilot svc ls
 	--app $p
 	--json
 */
-func (cli *CLI) SvcList(appName string) (*SvcListOutput, error) {
-	output, err := cli.exec(
+func (cli *ExecCLI) SvcList(appName string) (*SvcListOutput, error) {
+	output, err := cli.exec(context.Background(), nil,
 		exec.Command(cli.path, "svc", "ls",
 			"--app", appName,
 			"--json"))
@@ -303,9 +347,13 @@ copilot svc logs
 	--since $s
 	--env $e
 	--json
+
+When opts.Options.OnProgressEvent is set, SvcLogs streams log lines to the
+callback as they're produced instead of waiting for the command to exit, so
+a caller can render tailing logs in real time.
 */
-func (cli *CLI) SvcLogs(opts *SvcLogsRequest) ([]SvcLogsOutput, error) {
-	output, err := cli.exec(
+func (cli *ExecCLI) SvcLogs(opts *SvcLogsRequest) ([]SvcLogsOutput, error) {
+	output, err := cli.exec(opts.Context, opts.Options,
 		exec.Command(cli.path, "svc", "logs",
 			"--app", opts.AppName,
 			"--name", opts.Name,
@@ -323,8 +371,8 @@ copilot env delete
 	--name $n
 	--yes
 */
-func (cli *CLI) EnvDelete(envName string) (string, error) {
-	return cli.exec(
+func (cli *ExecCLI) EnvDelete(envName string) (string, error) {
+	return cli.exec(context.Background(), nil,
 		exec.Command(cli.path, "env", "delete",
 			"--name", envName,
 			"--yes"))
@@ -344,7 +392,7 @@ copilot env init
 	--override-public-cidrs (optional)
 	--override-vpc-cidr (optional)
 */
-func (cli *CLI) EnvInit(opts *EnvInitRequest) (string, error) {
+func (cli *ExecCLI) EnvInit(opts *EnvInitRequest) (string, error) {
 	commands := []string{"env", "init",
 		"--name", opts.EnvName,
 		"--app", opts.AppName,
@@ -364,7 +412,7 @@ func (cli *CLI) EnvInit(opts *EnvInitRequest) (string, error) {
 		commands = append(commands, "--override-vpc-cidr", opts.VPCConfig.CIDR, "--override-public-cidrs",
 			opts.VPCConfig.PublicSubnetCIDRs, "--override-private-cidrs", opts.VPCConfig.PrivateSubnetCIDRs)
 	}
-	return cli.exec(exec.Command(cli.path, commands...))
+	return cli.exec(opts.Context, opts.Options, exec.Command(cli.path, commands...))
 }
 
 /*EnvShow runs:
@@ -373,8 +421,8 @@ copilot env show
 	--name $n
 	--json
 */
-func (cli *CLI) EnvShow(opts *EnvShowRequest) (*EnvShowOutput, error) {
-	envJSON, envShowErr := cli.exec(
+func (cli *ExecCLI) EnvShow(opts *EnvShowRequest) (*EnvShowOutput, error) {
+	envJSON, envShowErr := cli.exec(opts.Context, opts.Options,
 		exec.Command(cli.path, "env", "show",
 			"--app", opts.AppName,
 			"--name", opts.EnvName,
@@ -391,8 +439,8 @@ copilot env ls
 	--app $a
 	--json
 */
-func (cli *CLI) EnvList(appName string) (*EnvListOutput, error) {
-	output, err := cli.exec(
+func (cli *ExecCLI) EnvList(appName string) (*EnvListOutput, error) {
+	output, err := cli.exec(context.Background(), nil,
 		exec.Command(cli.path, "env", "ls",
 			"--app", appName,
 			"--json"))
@@ -407,7 +455,7 @@ copilot app init $a
 	--domain $d (optionally)
 	--resource-tags $k1=$v1,$k2=$k2 (optionally)
 */
-func (cli *CLI) AppInit(opts *AppInitRequest) (string, error) {
+func (cli *ExecCLI) AppInit(opts *AppInitRequest) (string, error) {
 	commands := []string{"app", "init", opts.AppName}
 	if opts.Domain != "" {
 		commands = append(commands, "--domain", opts.Domain)
@@ -422,7 +470,7 @@ func (cli *CLI) AppInit(opts *AppInitRequest) (string, error) {
 		commands = append(commands, strings.Join(tags, ","))
 	}
 
-	return cli.exec(exec.Command(cli.path, commands...))
+	return cli.exec(opts.Context, opts.Options, exec.Command(cli.path, commands...))
 }
 
 /*AppShow runs:
@@ -430,8 +478,8 @@ copilot app show
 	--name $n
 	--json
 */
-func (cli *CLI) AppShow(appName string) (*AppShowOutput, error) {
-	output, err := cli.exec(
+func (cli *ExecCLI) AppShow(appName string) (*AppShowOutput, error) {
+	output, err := cli.exec(context.Background(), nil,
 		exec.Command(cli.path, "app", "show",
 			"--name", appName,
 			"--json"))
@@ -444,17 +492,17 @@ func (cli *CLI) AppShow(appName string) (*AppShowOutput, error) {
 /*AppList runs:
 copilot app ls
 */
-func (cli *CLI) AppList() (string, error) {
-	return cli.exec(exec.Command(cli.path, "app", "ls"))
+func (cli *ExecCLI) AppList() (string, error) {
+	return cli.exec(context.Background(), nil, exec.Command(cli.path, "app", "ls"))
 }
 
 /*AppDelete runs:
 copilot app delete --yes
 */
-func (cli *CLI) AppDelete() (string, error) {
+func (cli *ExecCLI) AppDelete() (string, error) {
 	commands := []string{"app", "delete", "--yes"}
 
-	return cli.exec(
+	return cli.exec(context.Background(), nil,
 		exec.Command(cli.path, commands...))
 }
 
@@ -468,8 +516,12 @@ copilot task run
 	--env-vars $e1=$v1,$e2=$v2 (optionally)
 	--default (optionally)
 	--follow (optionally)
+
+When opts.Follow is set, pair it with opts.Options.OnStdout (or
+OnProgressEvent) to observe the task's output as it streams instead of
+blocking until the process exits.
 */
-func (cli *CLI) TaskRun(input *TaskRunInput) (string, error) {
+func (cli *ExecCLI) TaskRun(input *TaskRunInput) (string, error) {
 	commands := []string{"task", "run", "-n", input.GroupName, "--dockerfile", input.Dockerfile}
 
 	if input.Image != "" {
@@ -500,21 +552,5 @@ func (cli *CLI) TaskRun(input *TaskRunInput) (string, error) {
 		commands = append(commands, "--follow")
 	}
 
-	return cli.exec(exec.Command(cli.path, commands...))
-}
-
-func (cli *CLI) exec(command *exec.Cmd) (string, error) {
-	// Turn off colors
-	command.Env = append(os.Environ(), "COLOR=false")
-	sess, err := gexec.Start(command, ginkgo.GinkgoWriter, ginkgo.GinkgoWriter)
-	if err != nil {
-		return "", err
-	}
-
-	contents := sess.Wait(100000000).Out.Contents()
-	if exitCode := sess.ExitCode(); exitCode != 0 {
-		return string(contents), fmt.Errorf("received non 0 exit code")
-	}
-
-	return string(contents), nil
+	return cli.exec(input.Context, input.Options, exec.Command(cli.path, commands...))
 }
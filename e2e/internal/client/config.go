@@ -0,0 +1,220 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CLIConfig configures how NewExecCLIWithConfig locates and validates the
+// copilot binary to drive, instead of the hard-coded /bin/copilot that
+// NewExecCLI assumes.
+type CLIConfig struct {
+	// Path, if set, is used verbatim as the copilot binary to run.
+	Path string
+
+	// PathFromEnv is the name of an environment variable (e.g. "COPILOT_BIN")
+	// that, if set, names the copilot binary to run. Takes precedence over Path.
+	PathFromEnv string
+
+	// LookPath, if true, falls back to resolving "copilot" on $PATH when
+	// neither Path nor PathFromEnv yields a binary.
+	LookPath bool
+
+	// Version, if set, constrains the resolved binary to a semver range
+	// (e.g. ">=1.20.0 <1.22.0"). NewExecCLIWithConfig runs `copilot --version`
+	// against it and returns a *VersionConstraintError if it isn't satisfied.
+	Version string
+}
+
+// NewExecCLIWithConfig returns a wrapper around ExecCLI using cfg to resolve
+// and, optionally, validate the copilot binary.
+func NewExecCLIWithConfig(cfg CLIConfig) (*ExecCLI, error) {
+	cliPath, err := cfg.resolvePath()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(cliPath); err != nil {
+		return nil, err
+	}
+
+	cli := &ExecCLI{path: cliPath}
+	if cfg.Version == "" {
+		return cli, nil
+	}
+
+	out, err := cli.Version()
+	if err != nil {
+		return nil, fmt.Errorf("get version of %s: %w", cliPath, err)
+	}
+	got, err := parseVersionOutput(out)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := got.satisfies(cfg.Version)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &VersionConstraintError{Path: cliPath, Got: got.String(), Constraint: cfg.Version}
+	}
+	return cli, nil
+}
+
+func (c CLIConfig) resolvePath() (string, error) {
+	if c.PathFromEnv != "" {
+		if p := os.Getenv(c.PathFromEnv); p != "" {
+			return p, nil
+		}
+	}
+	if c.Path != "" {
+		return c.Path, nil
+	}
+	if c.LookPath {
+		if p, err := exec.LookPath("copilot"); err == nil {
+			return p, nil
+		}
+	}
+	return filepath.Join("/", "bin", "copilot"), nil
+}
+
+// VersionConstraintError is returned when a resolved copilot binary's
+// version doesn't satisfy a CLIConfig.Version constraint.
+type VersionConstraintError struct {
+	Path       string
+	Got        string
+	Constraint string
+}
+
+func (e *VersionConstraintError) Error() string {
+	return fmt.Sprintf("%s reports version %s, which does not satisfy %q", e.Path, e.Got, e.Constraint)
+}
+
+// CLISet is a named collection of ExecCLI instances, one per requested
+// version, so a single Ginkgo spec can drive several copilot versions
+// side-by-side (e.g. "deploy with the old version, then svc deploy with the
+// new version").
+type CLISet map[string]*ExecCLI
+
+// NewCLISet resolves one ExecCLI per entry in versions. The special version
+// "local" resolves via the default NewExecCLI discovery; any other version
+// (e.g. "v1.20.0") is expected to be installed alongside the default binary
+// as "copilot-<version>", which is how the e2e Dockerfile lays out the
+// version matrix.
+func NewCLISet(versions []string) (CLISet, error) {
+	set := make(CLISet, len(versions))
+	for _, v := range versions {
+		if v == "local" {
+			cli, err := NewExecCLI()
+			if err != nil {
+				return nil, fmt.Errorf("resolve local copilot binary: %w", err)
+			}
+			set[v] = cli
+			continue
+		}
+		cli, err := NewExecCLIWithConfig(CLIConfig{
+			Path:    filepath.Join("/", "bin", fmt.Sprintf("copilot-%s", v)),
+			Version: v,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("resolve copilot %s binary: %w", v, err)
+		}
+		set[v] = cli
+	}
+	return set, nil
+}
+
+// semver is a parsed major.minor.patch version.
+type semver struct {
+	major, minor, patch int
+}
+
+func (v semver) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+}
+
+func (v semver) compare(o semver) int {
+	switch {
+	case v.major != o.major:
+		return v.major - o.major
+	case v.minor != o.minor:
+		return v.minor - o.minor
+	default:
+		return v.patch - o.patch
+	}
+}
+
+var versionOutputRe = regexp.MustCompile(`v?(\d+)\.(\d+)\.(\d+)`)
+
+// parseVersionOutput extracts the semver out of `copilot --version` output,
+// e.g. "copilot version: v1.21.0".
+func parseVersionOutput(out string) (semver, error) {
+	m := versionOutputRe.FindStringSubmatch(out)
+	if m == nil {
+		return semver{}, fmt.Errorf("no semver found in version output %q", strings.TrimSpace(out))
+	}
+	return newSemver(m[1], m[2], m[3])
+}
+
+func newSemver(major, minor, patch string) (semver, error) {
+	var v semver
+	var err error
+	if v.major, err = strconv.Atoi(major); err != nil {
+		return semver{}, err
+	}
+	if v.minor, err = strconv.Atoi(minor); err != nil {
+		return semver{}, err
+	}
+	if v.patch, err = strconv.Atoi(patch); err != nil {
+		return semver{}, err
+	}
+	return v, nil
+}
+
+// satisfies reports whether v satisfies every space-separated constraint in
+// expr, e.g. ">=1.20.0 <1.22.0".
+func (v semver) satisfies(expr string) (bool, error) {
+	for _, constraint := range strings.Fields(expr) {
+		op, rest := splitConstraintOp(constraint)
+		want, err := parseVersionOutput(rest)
+		if err != nil {
+			return false, fmt.Errorf("parse version constraint %q: %w", constraint, err)
+		}
+		cmp := v.compare(want)
+		var ok bool
+		switch op {
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		case "==", "=", "":
+			ok = cmp == 0
+		default:
+			return false, fmt.Errorf("unsupported constraint operator %q", op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func splitConstraintOp(constraint string) (op, rest string) {
+	for _, candidate := range []string{">=", "<=", "==", ">", "<", "="} {
+		if strings.HasPrefix(constraint, candidate) {
+			return candidate, strings.TrimPrefix(constraint, candidate)
+		}
+	}
+	return "", constraint
+}
@@ -0,0 +1,122 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import "testing"
+
+func TestParseVersionOutput(t *testing.T) {
+	testCases := map[string]struct {
+		in      string
+		wantErr bool
+		want    semver
+	}{
+		"parses a typical --version line": {
+			in:   "copilot version: v1.21.0\n",
+			want: semver{major: 1, minor: 21, patch: 0},
+		},
+		"parses a bare semver with no v prefix": {
+			in:   "1.2.3",
+			want: semver{major: 1, minor: 2, patch: 3},
+		},
+		"errors when no semver is present": {
+			in:      "not a version",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseVersionOutput(tc.in)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSemver_Satisfies(t *testing.T) {
+	testCases := map[string]struct {
+		v          semver
+		constraint string
+		want       bool
+	}{
+		"exact match with no operator": {
+			v:          semver{1, 20, 0},
+			constraint: "1.20.0",
+			want:       true,
+		},
+		"exact match fails on patch mismatch": {
+			v:          semver{1, 20, 1},
+			constraint: "1.20.0",
+			want:       false,
+		},
+		"greater-or-equal lower bound satisfied": {
+			v:          semver{1, 20, 0},
+			constraint: ">=1.20.0",
+			want:       true,
+		},
+		"combined range satisfied": {
+			v:          semver{1, 21, 5},
+			constraint: ">=1.20.0 <1.22.0",
+			want:       true,
+		},
+		"combined range violated by upper bound": {
+			v:          semver{1, 22, 0},
+			constraint: ">=1.20.0 <1.22.0",
+			want:       false,
+		},
+		"strictly less than": {
+			v:          semver{1, 19, 9},
+			constraint: "<1.20.0",
+			want:       true,
+		},
+		"strictly greater than": {
+			v:          semver{1, 20, 1},
+			constraint: ">1.20.0",
+			want:       true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.v.satisfies(tc.constraint)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("satisfies(%q) = %v, want %v", tc.constraint, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSplitConstraintOp(t *testing.T) {
+	testCases := map[string]struct {
+		in     string
+		wantOp string
+		wantV  string
+	}{
+		">= binds before bare >": {in: ">=1.2.3", wantOp: ">=", wantV: "1.2.3"},
+		"<= binds before bare <": {in: "<=1.2.3", wantOp: "<=", wantV: "1.2.3"},
+		"no operator":            {in: "1.2.3", wantOp: "", wantV: "1.2.3"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			op, v := splitConstraintOp(tc.in)
+			if op != tc.wantOp || v != tc.wantV {
+				t.Errorf("splitConstraintOp(%q) = (%q, %q), want (%q, %q)", tc.in, op, v, tc.wantOp, tc.wantV)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Kind classifies why a copilot invocation failed.
+type Kind int
+
+// Kinds of classified CLI failures.
+const (
+	KindUnknown Kind = iota
+	KindAppExists
+	KindStackRollback
+	KindAuthExpired
+	KindDockerBuild
+	KindQuotaExceeded
+)
+
+// CLIError is returned when a copilot invocation exits non-zero. It carries
+// the exit code, full stdout/stderr, the command that was run, and a
+// classified Kind, so callers can distinguish "app already exists" from
+// "stack rollback" from "docker build failed" instead of pattern-matching
+// an opaque error string themselves.
+type CLIError struct {
+	ExitCode int
+	Stdout   string
+	Stderr   string
+	Command  string
+	Kind     Kind
+}
+
+func (e *CLIError) Error() string {
+	return fmt.Sprintf("%s: exit code %d: %s", e.Command, e.ExitCode, strings.TrimSpace(e.Stderr))
+}
+
+// Is lets errors.Is(err, client.ErrStackRollback) (and friends) match any
+// *CLIError of the same Kind, regardless of exit code or message.
+func (e *CLIError) Is(target error) bool {
+	t, ok := target.(*CLIError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// Sentinel errors for use with errors.Is, e.g.:
+//
+//	if errors.Is(err, client.ErrStackRollback) { ... }
+var (
+	ErrAppExists     = &CLIError{Kind: KindAppExists}
+	ErrStackRollback = &CLIError{Kind: KindStackRollback}
+	ErrAuthExpired   = &CLIError{Kind: KindAuthExpired}
+	ErrDockerBuild   = &CLIError{Kind: KindDockerBuild}
+	ErrQuotaExceeded = &CLIError{Kind: KindQuotaExceeded}
+)
+
+// classifiers matches stderr (and, for CFN stack events, lines copilot
+// prints while polling a stack) against patterns for each known failure
+// Kind. Order matters: the first match wins.
+var classifiers = []struct {
+	kind    Kind
+	pattern *regexp.Regexp
+}{
+	{KindAppExists, regexp.MustCompile(`(?i)already exists`)},
+	{KindStackRollback, regexp.MustCompile(`(?i)ROLLBACK_(COMPLETE|IN_PROGRESS|FAILED)|UPDATE_ROLLBACK`)},
+	{KindAuthExpired, regexp.MustCompile(`(?i)ExpiredToken|token has expired|could not be refreshed`)},
+	{KindDockerBuild, regexp.MustCompile(`(?i)docker build|failed to build image`)},
+	{KindQuotaExceeded, regexp.MustCompile(`(?i)LimitExceeded|quota`)},
+}
+
+// eventClassifiers matches a CFN ProgressEvent's Status/Reason against each
+// known failure Kind. Checked before classifiers, since stack-rollback and
+// quota failures usually surface through the --json event stream on stdout
+// rather than through stderr text.
+var eventClassifiers = []struct {
+	kind    Kind
+	pattern *regexp.Regexp
+}{
+	{KindStackRollback, regexp.MustCompile(`(?i)ROLLBACK_(COMPLETE|IN_PROGRESS|FAILED)|UPDATE_ROLLBACK`)},
+	{KindQuotaExceeded, regexp.MustCompile(`(?i)LimitExceeded|quota`)},
+	{KindAuthExpired, regexp.MustCompile(`(?i)ExpiredToken|token has expired|could not be refreshed`)},
+}
+
+// classify determines a failure Kind from the CFN progress events scraped
+// off stdout and, failing that, from stderr patterns.
+func classify(stderr string, events []ProgressEvent) Kind {
+	for _, evt := range events {
+		for _, c := range eventClassifiers {
+			if c.pattern.MatchString(evt.Status) || c.pattern.MatchString(evt.Reason) {
+				return c.kind
+			}
+		}
+	}
+	for _, c := range classifiers {
+		if c.pattern.MatchString(stderr) {
+			return c.kind
+		}
+	}
+	return KindUnknown
+}
+
+// progressEventsFromStdout scrapes any JSON ProgressEvent lines out of
+// stdout, in the same format streamLines parses for OnProgressEvent. Lines
+// that aren't valid ProgressEvent JSON are skipped.
+func progressEventsFromStdout(stdout string) []ProgressEvent {
+	var events []ProgressEvent
+	for _, line := range strings.Split(stdout, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var evt ProgressEvent
+		if err := json.Unmarshal([]byte(line), &evt); err != nil {
+			continue
+		}
+		if evt.Resource == "" && evt.Status == "" && evt.Reason == "" && evt.Message == "" {
+			continue
+		}
+		events = append(events, evt)
+	}
+	return events
+}
+
+func newCLIError(command string, exitCode int, stdout, stderr string) *CLIError {
+	return &CLIError{
+		ExitCode: exitCode,
+		Stdout:   stdout,
+		Stderr:   stderr,
+		Command:  command,
+		Kind:     classify(stderr, progressEventsFromStdout(stdout)),
+	}
+}
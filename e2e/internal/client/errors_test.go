@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	testCases := map[string]struct {
+		stderr string
+		stdout string
+		want   Kind
+	}{
+		"app already exists in stderr": {
+			stderr: "app copilot-app already exists\n",
+			want:   KindAppExists,
+		},
+		"stack rollback surfaced via a stdout progress event": {
+			stdout: `{"resource":"my-stack","status":"ROLLBACK_COMPLETE","reason":"unresolvable resource"}` + "\n",
+			want:   KindStackRollback,
+		},
+		"stack rollback event wins even if stderr looks unrelated": {
+			stdout: `{"resource":"my-stack","status":"UPDATE_ROLLBACK_IN_PROGRESS"}` + "\n",
+			stderr: "deployment failed\n",
+			want:   KindStackRollback,
+		},
+		"quota exceeded via stderr": {
+			stderr: "LimitExceededException: too many VPCs\n",
+			want:   KindQuotaExceeded,
+		},
+		"docker build failure": {
+			stderr: "error: failed to build image: docker build exited with code 1\n",
+			want:   KindDockerBuild,
+		},
+		"unrecognized stderr classifies as unknown": {
+			stderr: "something went wrong\n",
+			want:   KindUnknown,
+		},
+		"non-JSON stdout lines are ignored, falling back to stderr": {
+			stdout: "not json\nalso not json\n",
+			stderr: "app already exists\n",
+			want:   KindAppExists,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := classify(tc.stderr, progressEventsFromStdout(tc.stdout))
+			if got != tc.want {
+				t.Errorf("classify() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCLIError_Is(t *testing.T) {
+	err := &CLIError{Kind: KindStackRollback, Command: "copilot svc deploy", ExitCode: 1}
+
+	if !errors.Is(err, ErrStackRollback) {
+		t.Errorf("expected errors.Is(err, ErrStackRollback) to be true")
+	}
+	if errors.Is(err, ErrAppExists) {
+		t.Errorf("expected errors.Is(err, ErrAppExists) to be false")
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+)
+
+// PipelineInitRequest contains the parameters for calling copilot pipeline init.
+type PipelineInitRequest struct {
+	AppName      string
+	Environments []string
+	GitHubURL    string
+	GitHubBranch string
+
+	Context context.Context
+	Options *ExecOptions
+}
+
+// JobInitRequest contains the parameters for calling copilot job init.
+type JobInitRequest struct {
+	AppName    string
+	Name       string
+	JobType    string
+	Dockerfile string
+	Schedule   string
+
+	Context context.Context
+	Options *ExecOptions
+}
+
+// JobDeployInput contains the parameters for calling copilot job deploy.
+type JobDeployInput struct {
+	Name     string
+	EnvName  string
+	ImageTag string
+
+	Context context.Context
+	Options *ExecOptions
+}
+
+// JobLogsRequest contains the parameters for calling copilot job logs.
+type JobLogsRequest struct {
+	AppName string
+	EnvName string
+	Name    string
+	Since   string
+
+	Context context.Context
+	Options *ExecOptions
+}
+
+// StorageInitRequest contains the parameters for calling copilot storage init.
+type StorageInitRequest struct {
+	AppName         string
+	Name            string
+	StorageType     string
+	WorkloadName    string
+	LifecyclePolicy string
+
+	Context context.Context
+	Options *ExecOptions
+}
+
+// SecretInitRequest contains the parameters for calling copilot secret init.
+type SecretInitRequest struct {
+	AppName string
+	Name    string
+	// Values maps an environment name to the secret's value in that environment.
+	Values map[string]string
+
+	Context context.Context
+	Options *ExecOptions
+}
+
+// DeployRequest contains the parameters for calling copilot deploy.
+type DeployRequest struct {
+	Name    string
+	EnvName string
+
+	Context context.Context
+	Options *ExecOptions
+}
+
+/*PipelineInit runs:
+copilot pipeline init
+	--app $a
+	--environments $e1,$e2
+	--github-url $u (optionally)
+	--git-branch $b (optionally)
+*/
+func (cli *ExecCLI) PipelineInit(opts *PipelineInitRequest) (string, error) {
+	commands := []string{"pipeline", "init",
+		"--app", opts.AppName,
+		"--environments", strings.Join(opts.Environments, ","),
+	}
+	if opts.GitHubURL != "" {
+		commands = append(commands, "--github-url", opts.GitHubURL)
+	}
+	if opts.GitHubBranch != "" {
+		commands = append(commands, "--git-branch", opts.GitHubBranch)
+	}
+	return cli.exec(opts.Context, opts.Options, exec.Command(cli.path, commands...))
+}
+
+/*PipelineUpdate runs:
+copilot pipeline update
+	--app $a
+	--yes
+*/
+func (cli *ExecCLI) PipelineUpdate(appName string) (string, error) {
+	return cli.exec(context.Background(), nil,
+		exec.Command(cli.path, "pipeline", "update",
+			"--app", appName,
+			"--yes"))
+}
+
+/*PipelineShow runs:
+copilot pipeline show
+	--app $a
+	--json
+*/
+func (cli *ExecCLI) PipelineShow(appName string) (*PipelineShowOutput, error) {
+	output, err := cli.exec(context.Background(), nil,
+		exec.Command(cli.path, "pipeline", "show",
+			"--app", appName,
+			"--json"))
+	if err != nil {
+		return nil, err
+	}
+	return toPipelineShowOutput(output)
+}
+
+/*PipelineStatus runs:
+copilot pipeline status
+	--app $a
+	--json
+*/
+func (cli *ExecCLI) PipelineStatus(appName string) (*PipelineStatusOutput, error) {
+	output, err := cli.exec(context.Background(), nil,
+		exec.Command(cli.path, "pipeline", "status",
+			"--app", appName,
+			"--json"))
+	if err != nil {
+		return nil, err
+	}
+	return toPipelineStatusOutput(output)
+}
+
+/*JobInit runs:
+copilot job init
+	--app $a
+	--name $n
+	--job-type $t
+	--dockerfile $d
+	--schedule $s
+*/
+func (cli *ExecCLI) JobInit(opts *JobInitRequest) (string, error) {
+	return cli.exec(opts.Context, opts.Options,
+		exec.Command(cli.path, "job", "init",
+			"--app", opts.AppName,
+			"--name", opts.Name,
+			"--job-type", opts.JobType,
+			"--dockerfile", opts.Dockerfile,
+			"--schedule", opts.Schedule))
+}
+
+/*JobDeploy runs:
+copilot job deploy
+	--name $n
+	--env $e
+	--tag $t
+*/
+func (cli *ExecCLI) JobDeploy(opts *JobDeployInput) (string, error) {
+	return cli.exec(opts.Context, opts.Options,
+		exec.Command(cli.path, "job", "deploy",
+			"--name", opts.Name,
+			"--env", opts.EnvName,
+			"--tag", opts.ImageTag))
+}
+
+/*JobLogs runs:
+copilot job logs
+	--app $p
+	--name $n
+	--since $s
+	--env $e
+	--json
+*/
+func (cli *ExecCLI) JobLogs(opts *JobLogsRequest) ([]SvcLogsOutput, error) {
+	output, err := cli.exec(opts.Context, opts.Options,
+		exec.Command(cli.path, "job", "logs",
+			"--app", opts.AppName,
+			"--name", opts.Name,
+			"--since", opts.Since,
+			"--env", opts.EnvName,
+			"--json"))
+	if err != nil {
+		return nil, err
+	}
+	return toSvcLogsOutput(output)
+}
+
+/*JobList runs:
+copilot job ls
+	--app $a
+	--json
+*/
+func (cli *ExecCLI) JobList(appName string) (*JobStatusOutput, error) {
+	output, err := cli.exec(context.Background(), nil,
+		exec.Command(cli.path, "job", "ls",
+			"--app", appName,
+			"--json"))
+	if err != nil {
+		return nil, err
+	}
+	return toJobStatusOutput(output)
+}
+
+/*StorageInit runs:
+copilot storage init
+	--app $a
+	--name $n
+	--storage-type $t
+	--workload $w
+	--lifecycle $l (optionally)
+*/
+func (cli *ExecCLI) StorageInit(opts *StorageInitRequest) (string, error) {
+	commands := []string{"storage", "init",
+		"--app", opts.AppName,
+		"--name", opts.Name,
+		"--storage-type", opts.StorageType,
+		"--workload", opts.WorkloadName,
+	}
+	if opts.LifecyclePolicy != "" {
+		commands = append(commands, "--lifecycle", opts.LifecyclePolicy)
+	}
+	return cli.exec(opts.Context, opts.Options, exec.Command(cli.path, commands...))
+}
+
+/*SecretInit runs:
+copilot secret init
+	--app $a
+	--name $n
+	--values $env1=$v1,$env2=$v2
+*/
+func (cli *ExecCLI) SecretInit(opts *SecretInitRequest) (string, error) {
+	values := make([]string, 0, len(opts.Values))
+	for env, val := range opts.Values {
+		values = append(values, env+"="+val)
+	}
+	return cli.exec(opts.Context, opts.Options,
+		exec.Command(cli.path, "secret", "init",
+			"--app", opts.AppName,
+			"--name", opts.Name,
+			"--values", strings.Join(values, ",")))
+}
+
+/*Deploy runs:
+copilot deploy
+	--name $n (optionally)
+	--env $e (optionally)
+*/
+func (cli *ExecCLI) Deploy(opts *DeployRequest) (string, error) {
+	commands := []string{"deploy"}
+	if opts.Name != "" {
+		commands = append(commands, "--name", opts.Name)
+	}
+	if opts.EnvName != "" {
+		commands = append(commands, "--env", opts.EnvName)
+	}
+	return cli.exec(opts.Context, opts.Options, exec.Command(cli.path, commands...))
+}
+
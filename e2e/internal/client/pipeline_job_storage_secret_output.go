@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import "encoding/json"
+
+// PipelineShowOutput is the JSON output of copilot pipeline show.
+type PipelineShowOutput struct {
+	Name    string   `json:"name"`
+	AppName string   `json:"app"`
+	Region  string   `json:"region,omitempty"`
+	Stages  []string `json:"stages,omitempty"`
+}
+
+// PipelineStatusOutput is the JSON output of copilot pipeline status.
+type PipelineStatusOutput struct {
+	Name   string                `json:"name"`
+	States []PipelineStageStatus `json:"stageStates"`
+}
+
+// PipelineStageStatus describes the status of a single pipeline stage.
+type PipelineStageStatus struct {
+	StageName string `json:"stageName"`
+	Status    string `json:"status"`
+}
+
+// JobStatusOutput is the JSON output of copilot job ls.
+type JobStatusOutput struct {
+	Jobs []JobDescription `json:"jobs"`
+}
+
+// JobDescription describes a single job returned by copilot job ls.
+type JobDescription struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Schedule string `json:"schedule,omitempty"`
+}
+
+func toPipelineShowOutput(jsonInput string) (*PipelineShowOutput, error) {
+	var output PipelineShowOutput
+	if err := json.Unmarshal([]byte(jsonInput), &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func toPipelineStatusOutput(jsonInput string) (*PipelineStatusOutput, error) {
+	var output PipelineStatusOutput
+	if err := json.Unmarshal([]byte(jsonInput), &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+func toJobStatusOutput(jsonInput string) (*JobStatusOutput, error) {
+	var output JobStatusOutput
+	if err := json.Unmarshal([]byte(jsonInput), &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
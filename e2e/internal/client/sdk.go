@@ -0,0 +1,375 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/aws/copilot-cli/internal/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+// Client is implemented by both the in-process SDK (sdk) and the
+// subprocess-based ExecCLI, so that e2e specs and third-party tooling can
+// drive Copilot without caring which transport sits underneath.
+type Client interface {
+	AppInit(opts *AppInitRequest) (string, error)
+	AppShow(appName string) (*AppShowOutput, error)
+	AppList() (string, error)
+	AppDelete() (string, error)
+
+	Init(opts *InitRequest) (string, error)
+
+	EnvInit(opts *EnvInitRequest) (string, error)
+	EnvShow(opts *EnvShowRequest) (*EnvShowOutput, error)
+	EnvList(appName string) (*EnvListOutput, error)
+	EnvDelete(envName string) (string, error)
+
+	SvcInit(opts *SvcInitRequest) (string, error)
+	SvcShow(opts *SvcShowRequest) (*SvcShowOutput, error)
+	SvcStatus(opts *SvcStatusRequest) (*SvcStatusOutput, error)
+	SvcDeploy(opts *SvcDeployInput) (string, error)
+	SvcList(appName string) (*SvcListOutput, error)
+	SvcLogs(opts *SvcLogsRequest) ([]SvcLogsOutput, error)
+	SvcDelete(serviceName string) (string, error)
+
+	TaskRun(input *TaskRunInput) (string, error)
+
+	PipelineInit(opts *PipelineInitRequest) (string, error)
+	PipelineUpdate(appName string) (string, error)
+	PipelineShow(appName string) (*PipelineShowOutput, error)
+	PipelineStatus(appName string) (*PipelineStatusOutput, error)
+
+	JobInit(opts *JobInitRequest) (string, error)
+	JobDeploy(opts *JobDeployInput) (string, error)
+	JobLogs(opts *JobLogsRequest) ([]SvcLogsOutput, error)
+	JobList(appName string) (*JobStatusOutput, error)
+
+	StorageInit(opts *StorageInitRequest) (string, error)
+	SecretInit(opts *SecretInitRequest) (string, error)
+	Deploy(opts *DeployRequest) (string, error)
+}
+
+var (
+	_ Client = (*sdk)(nil)
+	_ Client = (*ExecCLI)(nil)
+)
+
+// sdk is the in-process implementation of Client. Rather than forking a
+// "copilot" subprocess and scraping its stdout, it builds the very same
+// *cobra.Command each BuildXCmd in internal/pkg/cli builds for the real
+// copilot binary and executes it in-process. Driving the command this way
+// (rather than re-implementing it) keeps the Validate/Ask/Execute sequence,
+// flag defaulting, and deployer/describer construction that the real
+// command performs, since all of that lives inside the unexported opts type
+// each BuildXCmd's RunE closes over.
+type sdk struct{}
+
+// NewSDK returns a Client that drives Copilot in-process, without shelling
+// out to a prebuilt copilot binary. Tests and downstream tooling that only
+// need typed results and don't care about stdout/stderr formatting should
+// prefer this over ExecCLI.
+func NewSDK() Client {
+	return &sdk{}
+}
+
+// runCobraCmd executes cmd with args as if they'd been typed on the command
+// line, capturing stdout and stderr into separate buffers the same way
+// ExecCLI's subprocess does. A failing Execute is wrapped in a *CLIError
+// classified the same way ExecCLI's subprocess failures are, so
+// errors.Is(err, client.ErrStackRollback) (and friends) matches regardless of
+// which Client backend produced the error. There's no real process exit code
+// to report here, since cmd.Execute runs in-process, so ExitCode is always 1.
+func runCobraCmd(cmd *cobra.Command, args []string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs(args)
+	if err := cmd.Execute(); err != nil {
+		command := strings.Join(append([]string{cmd.CommandPath()}, args...), " ")
+		return stdout.String(), newCLIError(command, 1, stdout.String(), stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+func joinTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (s *sdk) AppInit(opts *AppInitRequest) (string, error) {
+	args := []string{opts.AppName}
+	if opts.Domain != "" {
+		args = append(args, "--domain", opts.Domain)
+	}
+	if len(opts.Tags) > 0 {
+		args = append(args, "--resource-tags", joinTags(opts.Tags))
+	}
+	return runCobraCmd(cli.BuildAppInitCmd(), args)
+}
+
+func (s *sdk) AppShow(appName string) (*AppShowOutput, error) {
+	out, err := runCobraCmd(cli.BuildAppShowCmd(), []string{"--name", appName, "--json"})
+	if err != nil {
+		return nil, err
+	}
+	return toAppShowOutput(out)
+}
+
+func (s *sdk) AppList() (string, error) {
+	return runCobraCmd(cli.BuildAppListCmd(), nil)
+}
+
+func (s *sdk) AppDelete() (string, error) {
+	return runCobraCmd(cli.BuildAppDeleteCmd(), []string{"--yes"})
+}
+
+func (s *sdk) Init(opts *InitRequest) (string, error) {
+	args := []string{
+		"--app", opts.AppName,
+		"--name", opts.WorkloadName,
+		"--type", opts.WorkloadType,
+		"--tag", opts.ImageTag,
+		"--dockerfile", opts.Dockerfile,
+		"--port", opts.SvcPort,
+	}
+	if opts.Deploy {
+		args = append(args, "--deploy")
+	}
+	return runCobraCmd(cli.BuildInitCmd(), args)
+}
+
+func (s *sdk) EnvInit(opts *EnvInitRequest) (string, error) {
+	args := []string{
+		"--name", opts.EnvName,
+		"--app", opts.AppName,
+		"--profile", opts.Profile,
+	}
+	if opts.Prod {
+		args = append(args, "--prod")
+	}
+	if !opts.CustomizedEnv {
+		args = append(args, "--default-config")
+	}
+	if opts.VPCImport.IsSet() {
+		args = append(args, "--import-vpc-id", opts.VPCImport.ID, "--import-public-subnets",
+			opts.VPCImport.PublicSubnetIDs, "--import-private-subnets", opts.VPCImport.PrivateSubnetIDs)
+	}
+	if (opts.VPCConfig != EnvInitRequestVPCConfig{}) {
+		args = append(args, "--override-vpc-cidr", opts.VPCConfig.CIDR, "--override-public-cidrs",
+			opts.VPCConfig.PublicSubnetCIDRs, "--override-private-cidrs", opts.VPCConfig.PrivateSubnetCIDRs)
+	}
+	return runCobraCmd(cli.BuildEnvInitCmd(), args)
+}
+
+func (s *sdk) EnvShow(opts *EnvShowRequest) (*EnvShowOutput, error) {
+	out, err := runCobraCmd(cli.BuildEnvShowCmd(), []string{"--app", opts.AppName, "--name", opts.EnvName, "--json"})
+	if err != nil {
+		return nil, err
+	}
+	return toEnvShowOutput(out)
+}
+
+func (s *sdk) EnvList(appName string) (*EnvListOutput, error) {
+	out, err := runCobraCmd(cli.BuildEnvListCmd(), []string{"--app", appName, "--json"})
+	if err != nil {
+		return nil, err
+	}
+	return toEnvListOutput(out)
+}
+
+func (s *sdk) EnvDelete(envName string) (string, error) {
+	return runCobraCmd(cli.BuildEnvDeleteCmd(), []string{"--name", envName, "--yes"})
+}
+
+func (s *sdk) SvcInit(opts *SvcInitRequest) (string, error) {
+	args := []string{
+		"--name", opts.Name,
+		"--svc-type", opts.SvcType,
+		"--dockerfile", opts.Dockerfile,
+	}
+	if opts.SvcPort != "" {
+		args = append(args, "--port", opts.SvcPort)
+	}
+	return runCobraCmd(cli.BuildSvcInitCmd(), args)
+}
+
+func (s *sdk) SvcShow(opts *SvcShowRequest) (*SvcShowOutput, error) {
+	out, err := runCobraCmd(cli.BuildSvcShowCmd(), []string{"--app", opts.AppName, "--name", opts.Name, "--json"})
+	if err != nil {
+		return nil, err
+	}
+	return toSvcShowOutput(out)
+}
+
+func (s *sdk) SvcStatus(opts *SvcStatusRequest) (*SvcStatusOutput, error) {
+	out, err := runCobraCmd(cli.BuildSvcStatusCmd(), []string{
+		"--app", opts.AppName, "--name", opts.Name, "--env", opts.EnvName, "--json",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toSvcStatusOutput(out)
+}
+
+func (s *sdk) SvcDeploy(opts *SvcDeployInput) (string, error) {
+	return runCobraCmd(cli.BuildSvcDeployCmd(), []string{
+		"--name", opts.Name, "--env", opts.EnvName, "--tag", opts.ImageTag,
+	})
+}
+
+func (s *sdk) SvcList(appName string) (*SvcListOutput, error) {
+	out, err := runCobraCmd(cli.BuildSvcListCmd(), []string{"--app", appName, "--json"})
+	if err != nil {
+		return nil, err
+	}
+	return toSvcListOutput(out)
+}
+
+func (s *sdk) SvcLogs(opts *SvcLogsRequest) ([]SvcLogsOutput, error) {
+	out, err := runCobraCmd(cli.BuildSvcLogsCmd(), []string{
+		"--app", opts.AppName, "--name", opts.Name, "--since", opts.Since, "--env", opts.EnvName, "--json",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toSvcLogsOutput(out)
+}
+
+func (s *sdk) SvcDelete(serviceName string) (string, error) {
+	return runCobraCmd(cli.BuildSvcDeleteCmd(), []string{"--name", serviceName, "--yes"})
+}
+
+func (s *sdk) TaskRun(input *TaskRunInput) (string, error) {
+	args := []string{"-n", input.GroupName, "--dockerfile", input.Dockerfile}
+	if input.Image != "" {
+		args = append(args, "--image", input.Image)
+	}
+	if input.AppName != "" {
+		args = append(args, "--app", input.AppName)
+	}
+	if input.Env != "" {
+		args = append(args, "--env", input.Env)
+	}
+	if input.Command != "" {
+		args = append(args, "--command", input.Command)
+	}
+	if input.EnvVars != "" {
+		args = append(args, "--env-vars", input.EnvVars)
+	}
+	if input.Default {
+		args = append(args, "--default")
+	}
+	if input.Follow {
+		args = append(args, "--follow")
+	}
+	return runCobraCmd(cli.BuildTaskRunCmd(), args)
+}
+
+func (s *sdk) PipelineInit(opts *PipelineInitRequest) (string, error) {
+	args := []string{
+		"--app", opts.AppName,
+		"--environments", strings.Join(opts.Environments, ","),
+	}
+	if opts.GitHubURL != "" {
+		args = append(args, "--github-url", opts.GitHubURL)
+	}
+	if opts.GitHubBranch != "" {
+		args = append(args, "--git-branch", opts.GitHubBranch)
+	}
+	return runCobraCmd(cli.BuildPipelineInitCmd(), args)
+}
+
+func (s *sdk) PipelineUpdate(appName string) (string, error) {
+	return runCobraCmd(cli.BuildPipelineUpdateCmd(), []string{"--app", appName, "--yes"})
+}
+
+func (s *sdk) PipelineShow(appName string) (*PipelineShowOutput, error) {
+	out, err := runCobraCmd(cli.BuildPipelineShowCmd(), []string{"--app", appName, "--json"})
+	if err != nil {
+		return nil, err
+	}
+	return toPipelineShowOutput(out)
+}
+
+func (s *sdk) PipelineStatus(appName string) (*PipelineStatusOutput, error) {
+	out, err := runCobraCmd(cli.BuildPipelineStatusCmd(), []string{"--app", appName, "--json"})
+	if err != nil {
+		return nil, err
+	}
+	return toPipelineStatusOutput(out)
+}
+
+func (s *sdk) JobInit(opts *JobInitRequest) (string, error) {
+	return runCobraCmd(cli.BuildJobInitCmd(), []string{
+		"--app", opts.AppName,
+		"--name", opts.Name,
+		"--job-type", opts.JobType,
+		"--dockerfile", opts.Dockerfile,
+		"--schedule", opts.Schedule,
+	})
+}
+
+func (s *sdk) JobDeploy(opts *JobDeployInput) (string, error) {
+	return runCobraCmd(cli.BuildJobDeployCmd(), []string{
+		"--name", opts.Name, "--env", opts.EnvName, "--tag", opts.ImageTag,
+	})
+}
+
+func (s *sdk) JobLogs(opts *JobLogsRequest) ([]SvcLogsOutput, error) {
+	out, err := runCobraCmd(cli.BuildJobLogsCmd(), []string{
+		"--app", opts.AppName, "--name", opts.Name, "--since", opts.Since, "--env", opts.EnvName, "--json",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toSvcLogsOutput(out)
+}
+
+func (s *sdk) JobList(appName string) (*JobStatusOutput, error) {
+	out, err := runCobraCmd(cli.BuildJobListCmd(), []string{"--app", appName, "--json"})
+	if err != nil {
+		return nil, err
+	}
+	return toJobStatusOutput(out)
+}
+
+func (s *sdk) StorageInit(opts *StorageInitRequest) (string, error) {
+	args := []string{
+		"--app", opts.AppName,
+		"--name", opts.Name,
+		"--storage-type", opts.StorageType,
+		"--workload", opts.WorkloadName,
+	}
+	if opts.LifecyclePolicy != "" {
+		args = append(args, "--lifecycle", opts.LifecyclePolicy)
+	}
+	return runCobraCmd(cli.BuildStorageInitCmd(), args)
+}
+
+func (s *sdk) SecretInit(opts *SecretInitRequest) (string, error) {
+	values := make([]string, 0, len(opts.Values))
+	for env, val := range opts.Values {
+		values = append(values, env+"="+val)
+	}
+	return runCobraCmd(cli.BuildSecretInitCmd(), []string{
+		"--app", opts.AppName, "--name", opts.Name, "--values", strings.Join(values, ","),
+	})
+}
+
+func (s *sdk) Deploy(opts *DeployRequest) (string, error) {
+	args := []string{}
+	if opts.Name != "" {
+		args = append(args, "--name", opts.Name)
+	}
+	if opts.EnvName != "" {
+		args = append(args, "--env", opts.EnvName)
+	}
+	return runCobraCmd(cli.BuildDeployCmd(), args)
+}
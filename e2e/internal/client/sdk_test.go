@@ -0,0 +1,76 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRunCobraCmd_ClassifiesFailures(t *testing.T) {
+	cmd := &cobra.Command{
+		Use:          "mock",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.PrintErrln("app my-app already exists")
+			return errors.New("exit status 1")
+		},
+	}
+
+	_, err := runCobraCmd(cmd, nil)
+
+	if !errors.Is(err, ErrAppExists) {
+		t.Errorf("expected errors.Is(err, ErrAppExists) to be true, got %v", err)
+	}
+}
+
+func TestRunCobraCmd_SeparatesStdoutAndStderr(t *testing.T) {
+	cmd := &cobra.Command{
+		Use:          "mock",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Println("progress update")
+			cmd.PrintErrln("app my-app already exists")
+			return errors.New("exit status 1")
+		},
+	}
+
+	out, err := runCobraCmd(cmd, nil)
+
+	if out != "progress update\n" {
+		t.Errorf("got stdout %q, want %q", out, "progress update\n")
+	}
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) {
+		t.Fatalf("expected a *CLIError, got %v (%T)", err, err)
+	}
+	if cliErr.Stdout != "progress update\n" {
+		t.Errorf("cliErr.Stdout = %q, want %q", cliErr.Stdout, "progress update\n")
+	}
+	if !strings.Contains(cliErr.Stderr, "app my-app already exists") {
+		t.Errorf("cliErr.Stderr = %q, want it to contain %q", cliErr.Stderr, "app my-app already exists")
+	}
+}
+
+func TestRunCobraCmd_Success(t *testing.T) {
+	cmd := &cobra.Command{
+		Use: "mock",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Println("all good")
+			return nil
+		},
+	}
+
+	out, err := runCobraCmd(cmd, nil)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out != "all good\n" {
+		t.Errorf("got output %q, want %q", out, "all good\n")
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/onsi/ginkgo"
+)
+
+// maxLineSize caps how long a single line of stdout/stderr can be before
+// streamLines gives up on it. It's well above bufio.Scanner's 64KB default so
+// that a long CFN event, docker build log line, or --json blob doesn't make
+// Scan() fail permanently partway through a stream.
+const maxLineSize = 10 * 1024 * 1024
+
+// ExecOptions customizes how a single copilot invocation is executed and
+// observed. All fields are optional; a zero-value ExecOptions behaves like
+// the historical buffer-then-return behavior.
+//
+// OnStdout and OnStderr are driven by separate goroutines reading the
+// subprocess's stdout and stderr pipes, so they may run concurrently with
+// each other; each is only ever called sequentially, one line at a time, on
+// its own goroutine. Callbacks that touch shared state must synchronize it
+// themselves.
+type ExecOptions struct {
+	// OnStdout, if set, is called once per line of stdout as it's produced.
+	OnStdout func(line string)
+
+	// OnStderr, if set, is called once per line of stderr as it's produced.
+	OnStderr func(line string)
+
+	// OnProgressEvent, if set, is called for every JSON event copilot emits
+	// on stdout when run with --json: CFN stack events, docker build steps,
+	// and log lines from `svc logs` or `task run --follow`. Lines that
+	// aren't valid ProgressEvent JSON are skipped. It's called from the same
+	// goroutine as OnStdout, interleaved with it.
+	OnProgressEvent func(ProgressEvent)
+}
+
+// ProgressEvent is a single structured event parsed out of a copilot --json
+// event stream.
+type ProgressEvent struct {
+	Resource string `json:"resource,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// exec runs command to completion, streaming stdout/stderr lines to opts's
+// callbacks as they're produced while still buffering and returning the full
+// stdout, so existing callers that only care about the final string keep
+// working unchanged. If ctx is canceled or its deadline passes, the command
+// is killed and ctx.Err() is returned.
+func (cli *ExecCLI) exec(ctx context.Context, opts *ExecOptions, command *exec.Cmd) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	streamed := exec.CommandContext(ctx, command.Path, command.Args[1:]...)
+	streamed.Env = append(os.Environ(), "COLOR=false")
+
+	if opts == nil {
+		var stdoutBuf, stderrBuf bytes.Buffer
+		streamed.Stdout = io.MultiWriter(&stdoutBuf, ginkgo.GinkgoWriter)
+		streamed.Stderr = io.MultiWriter(&stderrBuf, ginkgo.GinkgoWriter)
+
+		runErr := streamed.Run()
+		contents := stdoutBuf.String()
+		if runErr != nil {
+			if ctx.Err() != nil {
+				return contents, ctx.Err()
+			}
+			return contents, newCLIError(streamed.String(), streamed.ProcessState.ExitCode(), contents, stderrBuf.String())
+		}
+		return contents, nil
+	}
+
+	stdout, err := streamed.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	stderr, err := streamed.StderrPipe()
+	if err != nil {
+		return "", err
+	}
+
+	if err := streamed.Start(); err != nil {
+		return "", err
+	}
+
+	var stdoutBuf, stderrBuf safeBuffer
+	var stdoutErr, stderrErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		stdoutErr = cli.streamLines(stdout, &stdoutBuf, opts.OnStdout, opts.OnProgressEvent)
+	}()
+	go func() {
+		defer wg.Done()
+		stderrErr = cli.streamLines(stderr, &stderrBuf, opts.OnStderr, nil)
+	}()
+	wg.Wait()
+
+	err = streamed.Wait()
+	contents := stdoutBuf.String()
+	if ctx.Err() != nil {
+		return contents, ctx.Err()
+	}
+	if err != nil {
+		return contents, newCLIError(streamed.String(), streamed.ProcessState.ExitCode(), contents, stderrBuf.String())
+	}
+	if stdoutErr != nil {
+		return contents, fmt.Errorf("read stdout of %s: %w", streamed.String(), stdoutErr)
+	}
+	if stderrErr != nil {
+		return contents, fmt.Errorf("read stderr of %s: %w", streamed.String(), stderrErr)
+	}
+	return contents, nil
+}
+
+// streamLines reads r line by line, appending each line to buf (if set),
+// invoking onLine for it, and additionally trying to parse it as a
+// ProgressEvent for onEvent. It returns any error bufio.Scanner encountered,
+// including hitting its buffer's max token size, so callers don't silently
+// lose the rest of a stream.
+func (cli *ExecCLI) streamLines(r io.Reader, buf *safeBuffer, onLine func(string), onEvent func(ProgressEvent)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if buf != nil {
+			buf.WriteString(line)
+			buf.WriteString("\n")
+		}
+		if onLine != nil {
+			onLine(line)
+		}
+		if onEvent != nil {
+			var evt ProgressEvent
+			if err := json.Unmarshal([]byte(line), &evt); err == nil && evt != (ProgressEvent{}) {
+				onEvent(evt)
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// safeBuffer is a mutex-guarded string buffer, safe for concurrent writes from the
+// stdout/stderr streaming goroutines.
+type safeBuffer struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (b *safeBuffer) WriteString(s string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, s...)
+}
+
+func (b *safeBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecCLI_Exec_ContextCancellationKillsCommand(t *testing.T) {
+	cli := &ExecCLI{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := cli.exec(ctx, &ExecOptions{}, exec.Command("sleep", "30"))
+		done <- err
+	}()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("exec() err = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("exec() did not return after the context was canceled")
+	}
+}
+
+func TestExecCLI_Exec_StreamsCallbacksPerLine(t *testing.T) {
+	cli := &ExecCLI{}
+	var stdoutLines, stderrLines []string
+	var events []ProgressEvent
+
+	_, err := cli.exec(context.Background(), &ExecOptions{
+		OnStdout:        func(line string) { stdoutLines = append(stdoutLines, line) },
+		OnStderr:        func(line string) { stderrLines = append(stderrLines, line) },
+		OnProgressEvent: func(evt ProgressEvent) { events = append(events, evt) },
+	}, exec.Command("sh", "-c",
+		`echo out1; echo '{"resource":"my-stack","status":"CREATE_COMPLETE"}'; echo err1 1>&2`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantStdout := []string{"out1", `{"resource":"my-stack","status":"CREATE_COMPLETE"}`}
+	if len(stdoutLines) != len(wantStdout) || stdoutLines[0] != wantStdout[0] || stdoutLines[1] != wantStdout[1] {
+		t.Errorf("OnStdout lines = %v, want %v", stdoutLines, wantStdout)
+	}
+	if len(stderrLines) != 1 || stderrLines[0] != "err1" {
+		t.Errorf("OnStderr lines = %v, want [err1]", stderrLines)
+	}
+	if len(events) != 1 || events[0].Resource != "my-stack" || events[0].Status != "CREATE_COMPLETE" {
+		t.Errorf("OnProgressEvent events = %v, want one CREATE_COMPLETE event for my-stack", events)
+	}
+}
+
+func TestExecCLI_Exec_HandlesLinesPastScannerDefault(t *testing.T) {
+	cli := &ExecCLI{}
+	// bufio.Scanner's default max token size is 64KB; a line past that used
+	// to make Scan() fail permanently and silently drop the rest of the
+	// stream.
+	longLine := strings.Repeat("a", 100*1024)
+	var stdoutLines []string
+
+	contents, err := cli.exec(context.Background(), &ExecOptions{
+		OnStdout: func(line string) { stdoutLines = append(stdoutLines, line) },
+	}, exec.Command("sh", "-c", "echo "+longLine+"; echo after"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(stdoutLines) != 2 || stdoutLines[0] != longLine || stdoutLines[1] != "after" {
+		t.Errorf("OnStdout fired for %d lines, want [<%d-byte line>, \"after\"]", len(stdoutLines), len(longLine))
+	}
+	if !strings.Contains(contents, "after") {
+		t.Errorf("contents = %q, want it to contain the line after the long one", contents)
+	}
+}
+
+func TestExecCLI_Exec_StreamingPathClassifiesFailure(t *testing.T) {
+	cli := &ExecCLI{}
+
+	_, err := cli.exec(context.Background(), &ExecOptions{}, exec.Command("sh", "-c",
+		`echo "app my-app already exists" 1>&2; exit 1`))
+
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) {
+		t.Fatalf("expected a *CLIError, got %v (%T)", err, err)
+	}
+	if cliErr.Kind != KindAppExists {
+		t.Errorf("cliErr.Kind = %v, want KindAppExists", cliErr.Kind)
+	}
+	if cliErr.ExitCode != 1 {
+		t.Errorf("cliErr.ExitCode = %d, want 1", cliErr.ExitCode)
+	}
+}